@@ -0,0 +1,62 @@
+// Copyright (c) 2018-2020 Splunk Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha2
+
+// SparkServiceExposureType selects how a single Spark master port is exposed.
+type SparkServiceExposureType string
+
+const (
+	// SparkServiceExposureDisabled omits the port from any generated Service.
+	SparkServiceExposureDisabled SparkServiceExposureType = "Disabled"
+
+	// SparkServiceExposureClusterIP exposes the port on a ClusterIP Service (the default).
+	SparkServiceExposureClusterIP SparkServiceExposureType = "ClusterIP"
+
+	// SparkServiceExposureNodePort exposes the port on a NodePort Service.
+	SparkServiceExposureNodePort SparkServiceExposureType = "NodePort"
+
+	// SparkServiceExposureLoadBalancer exposes the port on a LoadBalancer Service.
+	SparkServiceExposureLoadBalancer SparkServiceExposureType = "LoadBalancer"
+
+	// SparkServiceExposureIngress exposes the port through a networking/v1 Ingress,
+	// backed by its own ClusterIP Service.
+	SparkServiceExposureIngress SparkServiceExposureType = "Ingress"
+)
+
+// SparkServiceExposure configures how a single named Spark master port
+// (see GetSparkMasterPorts) is exposed outside the cluster.
+type SparkServiceExposure struct {
+	// Type selects the Service (or Ingress) kind used to expose the port.
+	Type SparkServiceExposureType `json:"type,omitempty"`
+
+	// LoadBalancerIP requests a specific address when Type is LoadBalancer.
+	LoadBalancerIP string `json:"loadBalancerIP,omitempty"`
+
+	// NodePort requests a specific node port when Type is NodePort.
+	NodePort int32 `json:"nodePort,omitempty"`
+
+	// Annotations are merged onto the generated Service when Type is
+	// LoadBalancer; they are ignored for every other Type.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// IngressClassName selects the IngressClass when Type is Ingress.
+	IngressClassName string `json:"ingressClassName,omitempty"`
+
+	// Host is the hostname routed to this port when Type is Ingress.
+	Host string `json:"host,omitempty"`
+
+	// TLSSecretName, when set, enables TLS on the Ingress using this Secret.
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+}
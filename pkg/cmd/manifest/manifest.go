@@ -0,0 +1,78 @@
+// Copyright (c) 2018-2020 Splunk Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package manifest implements the `splunk-operator manifest` CLI subcommand,
+// for GitOps users who want to review or commit the Kubernetes objects the
+// operator would create for a SplunkEnterprise resource's Spark components
+// instead of applying them live. It does not yet cover the Splunk
+// standalone/indexer/search-head resources the enterprise package builds.
+package manifest
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/splunk/splunk-operator/pkg/apis/enterprise/v1alpha2"
+	"github.com/splunk/splunk-operator/pkg/splunk/deploy"
+)
+
+// NewCommand returns the `manifest` subcommand. It reads a SplunkEnterprise
+// resource from --file and writes the Spark manifest deploy.GenerateSparkManifest
+// computes for it to --output (stdout by default).
+func NewCommand() *cobra.Command {
+	var file string
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "manifest",
+		Short: "Render the Kubernetes objects the operator would create for a SplunkEnterprise resource's Spark components",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(file, output)
+		},
+	}
+
+	cmd.Flags().StringVar(&file, "file", "", "path to a SplunkEnterprise resource YAML file")
+	cmd.Flags().StringVar(&output, "output", "", "path to write the manifest to (defaults to stdout)")
+	cmd.MarkFlagRequired("file") //nolint:errcheck
+
+	return cmd
+}
+
+func run(file string, output string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("manifest: reading %s: %s", file, err)
+	}
+
+	var cr v1alpha2.SplunkEnterprise
+	if err := yaml.Unmarshal(data, &cr); err != nil {
+		return fmt.Errorf("manifest: parsing %s: %s", file, err)
+	}
+
+	w := io.Writer(os.Stdout)
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("manifest: creating %s: %s", output, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return deploy.ExportSparkManifest(&cr, w)
+}
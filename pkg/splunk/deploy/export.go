@@ -0,0 +1,122 @@
+// Copyright (c) 2018-2020 Splunk Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"fmt"
+	"io"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/splunk/splunk-operator/pkg/apis/enterprise/v1alpha2"
+	"github.com/splunk/splunk-operator/pkg/splunk/spark"
+)
+
+// GenerateSparkManifest returns the ordered set of Deployments and Services
+// (plus an Ingress when any master port is exposed through Ingress) that the
+// operator would create for the Spark components of cr, without
+// applying any of them. It does not cover the Splunk standalone/indexer/
+// search-head resources the enterprise package builds, or any ConfigMaps/
+// Secrets — callers that need the full set of objects a live reconcile would
+// apply must still assemble those separately. Field values are fully
+// determined by cr, so repeated calls produce byte-identical objects, which
+// keeps a generated manifest safe to diff across reconciles.
+func GenerateSparkManifest(cr *v1alpha2.SplunkEnterprise) ([]ResourceObject, error) {
+	var objs []ResourceObject
+
+	masterPorts := spark.GetSparkMasterContainerPorts()
+	masterServicePorts := spark.GetSparkMasterServicePorts()
+	masterEnv := spark.GetSparkMasterConfiguration()
+	masterExposures := cr.Spec.SparkServiceExposure
+
+	masterDeployment, err := spark.GetSparkDeployment(cr, spark.SparkMaster, 1, masterEnv, masterPorts)
+	if err != nil {
+		return nil, fmt.Errorf("GenerateSparkManifest: spark master deployment: %s", err)
+	}
+	objs = append(objs, masterDeployment)
+
+	for _, service := range spark.GetSparkService(cr, spark.SparkMaster, false, masterServicePorts, masterExposures) {
+		objs = append(objs, service)
+	}
+	if hasIngressExposure(masterExposures) {
+		objs = append(objs, spark.GetSparkIngress(cr, spark.SparkMaster, masterServicePorts, masterExposures))
+	}
+
+	workerPorts := spark.GetSparkWorkerContainerPorts()
+	workerServicePorts := spark.GetSparkWorkerServicePorts()
+	workerEnv := spark.GetSparkWorkerConfiguration(cr.GetIdentifier())
+
+	workerDeployment, err := spark.GetSparkDeployment(cr, spark.SparkWorker, cr.Spec.Resources.SparkWorkerCount, workerEnv, workerPorts)
+	if err != nil {
+		return nil, fmt.Errorf("GenerateSparkManifest: spark worker deployment: %s", err)
+	}
+	objs = append(objs, workerDeployment)
+
+	for _, service := range spark.GetSparkService(cr, spark.SparkWorker, true, workerServicePorts, nil) {
+		objs = append(objs, service)
+	}
+
+	return objs, nil
+}
+
+// hasIngressExposure reports whether any port in exposures selects Ingress,
+// which is when GenerateSparkManifest also needs to emit a GetSparkIngress object.
+func hasIngressExposure(exposures map[string]v1alpha2.SparkServiceExposure) bool {
+	for _, exposure := range exposures {
+		if exposure.Type == v1alpha2.SparkServiceExposureIngress {
+			return true
+		}
+	}
+	return false
+}
+
+// ExportSparkManifest computes the Spark manifest for cr (see
+// GenerateSparkManifest) and writes it to w, combining GenerateSparkManifest
+// and WriteYAML into the single call the `manifest` CLI subcommand
+// (pkg/cmd/manifest) needs.
+func ExportSparkManifest(cr *v1alpha2.SplunkEnterprise, w io.Writer) error {
+	objs, err := GenerateSparkManifest(cr)
+	if err != nil {
+		return err
+	}
+	return WriteYAML(w, objs)
+}
+
+// WriteYAML writes objs to w as a multi-document YAML stream, one `---`
+// separated document per object, in the order given. Keys within each
+// document are sorted alphabetically, so the output is stable across
+// reconciles and safe to diff.
+func WriteYAML(w io.Writer, objs []ResourceObject) error {
+	for i, obj := range objs {
+		if i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+
+		// sigs.k8s.io/yaml marshals via JSON first and then re-encodes the
+		// result as YAML with every key sorted, so repeated exports are
+		// byte-for-byte stable.
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("WriteYAML: %s %q: %s", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetObjectMeta().GetName(), err)
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
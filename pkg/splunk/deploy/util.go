@@ -19,6 +19,7 @@ import (
 	"reflect"
 
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -143,6 +144,29 @@ func ApplyService(client client.Client, service *corev1.Service) error {
 	return err
 }
 
+// ApplyIngress creates or updates a Kubernetes Ingress
+func ApplyIngress(client client.Client, ingress *networkingv1.Ingress) error {
+	scopedLog := log.WithName("ApplyIngress").WithValues(
+		"name", ingress.GetObjectMeta().GetName(),
+		"namespace", ingress.GetObjectMeta().GetNamespace())
+
+	var oldIngress networkingv1.Ingress
+	namespacedName := types.NamespacedName{
+		Namespace: ingress.Namespace,
+		Name:      ingress.Name,
+	}
+
+	err := client.Get(context.TODO(), namespacedName, &oldIngress)
+	if err == nil {
+		// found existing Ingress: do nothing
+		scopedLog.Info("Found existing Ingress")
+	} else {
+		err = CreateResource(client, ingress)
+	}
+
+	return err
+}
+
 // MergePodUpdates looks for material differences between a Pod's current
 // config and a revised config. It merges material changes from revised to
 // current. This enables us to minimize updates. It returns true if there
@@ -169,6 +193,60 @@ func MergePodUpdates(current *corev1.PodTemplateSpec, revised *corev1.PodTemplat
 		result = true
 	}
 
+	// check for changes in PodSpec-level SecurityContext
+	if resources.CompareByMarshall(current.Spec.SecurityContext, revised.Spec.SecurityContext) {
+		scopedLog.Info("Pod SecurityContext differs",
+			"current", current.Spec.SecurityContext,
+			"revised", revised.Spec.SecurityContext)
+		current.Spec.SecurityContext = revised.Spec.SecurityContext
+		result = true
+	}
+
+	// check for changes in Tolerations
+	if resources.CompareTolerations(current.Spec.Tolerations, revised.Spec.Tolerations) {
+		scopedLog.Info("Pod Tolerations differ",
+			"current", current.Spec.Tolerations,
+			"revised", revised.Spec.Tolerations)
+		current.Spec.Tolerations = revised.Spec.Tolerations
+		result = true
+	}
+
+	// check for changes in NodeSelector
+	if resources.CompareNodeSelector(current.Spec.NodeSelector, revised.Spec.NodeSelector) {
+		scopedLog.Info("Pod NodeSelector differs",
+			"current", current.Spec.NodeSelector,
+			"revised", revised.Spec.NodeSelector)
+		current.Spec.NodeSelector = revised.Spec.NodeSelector
+		result = true
+	}
+
+	// check for changes in ServiceAccountName
+	if current.Spec.ServiceAccountName != revised.Spec.ServiceAccountName {
+		scopedLog.Info("Pod ServiceAccountName differs",
+			"current", current.Spec.ServiceAccountName,
+			"revised", revised.Spec.ServiceAccountName)
+		current.Spec.ServiceAccountName = revised.Spec.ServiceAccountName
+		result = true
+	}
+
+	// check for changes in ImagePullSecrets
+	if resources.CompareImagePullSecrets(current.Spec.ImagePullSecrets, revised.Spec.ImagePullSecrets) {
+		scopedLog.Info("Pod ImagePullSecrets differ",
+			"current", current.Spec.ImagePullSecrets,
+			"revised", revised.Spec.ImagePullSecrets)
+		current.Spec.ImagePullSecrets = revised.Spec.ImagePullSecrets
+		result = true
+	}
+
+	// check for changes in Volumes
+	if resources.CompareVolumes(current.Spec.Volumes, revised.Spec.Volumes) {
+		scopedLog.Info("Pod Volumes differ",
+			"current", current.Spec.Volumes,
+			"revised", revised.Spec.Volumes)
+		current.Spec.Volumes = revised.Spec.Volumes
+		result = true
+	}
+
 	// check for changes in container images; assume that the ordering is same for pods with > 1 container
 	if len(current.Spec.Containers) != len(revised.Spec.Containers) {
 		scopedLog.Info("Pod Container counts differ",
@@ -231,6 +309,78 @@ func MergePodUpdates(current *corev1.PodTemplateSpec, revised *corev1.PodTemplat
 				current.Spec.Containers[idx].Resources = revised.Spec.Containers[idx].Resources
 				result = true
 			}
+
+			// check Env
+			if resources.CompareEnvs(current.Spec.Containers[idx].Env, revised.Spec.Containers[idx].Env) {
+				scopedLog.Info("Pod Container Env differs",
+					"current", current.Spec.Containers[idx].Env,
+					"revised", revised.Spec.Containers[idx].Env)
+				current.Spec.Containers[idx].Env = revised.Spec.Containers[idx].Env
+				result = true
+			}
+
+			// check EnvFrom
+			if resources.CompareEnvFrom(current.Spec.Containers[idx].EnvFrom, revised.Spec.Containers[idx].EnvFrom) {
+				scopedLog.Info("Pod Container EnvFrom differs",
+					"current", current.Spec.Containers[idx].EnvFrom,
+					"revised", revised.Spec.Containers[idx].EnvFrom)
+				current.Spec.Containers[idx].EnvFrom = revised.Spec.Containers[idx].EnvFrom
+				result = true
+			}
+
+			// check Command
+			if !reflect.DeepEqual(current.Spec.Containers[idx].Command, revised.Spec.Containers[idx].Command) {
+				scopedLog.Info("Pod Container Command differs",
+					"current", current.Spec.Containers[idx].Command,
+					"revised", revised.Spec.Containers[idx].Command)
+				current.Spec.Containers[idx].Command = revised.Spec.Containers[idx].Command
+				result = true
+			}
+
+			// check Args
+			if !reflect.DeepEqual(current.Spec.Containers[idx].Args, revised.Spec.Containers[idx].Args) {
+				scopedLog.Info("Pod Container Args differ",
+					"current", current.Spec.Containers[idx].Args,
+					"revised", revised.Spec.Containers[idx].Args)
+				current.Spec.Containers[idx].Args = revised.Spec.Containers[idx].Args
+				result = true
+			}
+
+			// check LivenessProbe
+			if resources.CompareByMarshall(current.Spec.Containers[idx].LivenessProbe, revised.Spec.Containers[idx].LivenessProbe) {
+				scopedLog.Info("Pod Container LivenessProbe differs",
+					"current", current.Spec.Containers[idx].LivenessProbe,
+					"revised", revised.Spec.Containers[idx].LivenessProbe)
+				current.Spec.Containers[idx].LivenessProbe = revised.Spec.Containers[idx].LivenessProbe
+				result = true
+			}
+
+			// check ReadinessProbe
+			if resources.CompareByMarshall(current.Spec.Containers[idx].ReadinessProbe, revised.Spec.Containers[idx].ReadinessProbe) {
+				scopedLog.Info("Pod Container ReadinessProbe differs",
+					"current", current.Spec.Containers[idx].ReadinessProbe,
+					"revised", revised.Spec.Containers[idx].ReadinessProbe)
+				current.Spec.Containers[idx].ReadinessProbe = revised.Spec.Containers[idx].ReadinessProbe
+				result = true
+			}
+
+			// check StartupProbe
+			if resources.CompareByMarshall(current.Spec.Containers[idx].StartupProbe, revised.Spec.Containers[idx].StartupProbe) {
+				scopedLog.Info("Pod Container StartupProbe differs",
+					"current", current.Spec.Containers[idx].StartupProbe,
+					"revised", revised.Spec.Containers[idx].StartupProbe)
+				current.Spec.Containers[idx].StartupProbe = revised.Spec.Containers[idx].StartupProbe
+				result = true
+			}
+
+			// check per-container SecurityContext
+			if resources.CompareByMarshall(current.Spec.Containers[idx].SecurityContext, revised.Spec.Containers[idx].SecurityContext) {
+				scopedLog.Info("Pod Container SecurityContext differs",
+					"current", current.Spec.Containers[idx].SecurityContext,
+					"revised", revised.Spec.Containers[idx].SecurityContext)
+				current.Spec.Containers[idx].SecurityContext = revised.Spec.Containers[idx].SecurityContext
+				result = true
+			}
 		}
 	}
 
@@ -0,0 +1,196 @@
+// Copyright (c) 2018-2020 Splunk Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// basePodTemplate returns a minimal PodTemplateSpec that each test case
+// mutates via revisedFunc, so every case starts from the same baseline.
+func basePodTemplate() *corev1.PodTemplateSpec {
+	return &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "splunk",
+					Image: "splunk/splunk",
+					Env: []corev1.EnvVar{
+						{Name: "SPLUNK_ROLE", Value: "splunk_spark_master"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestMergePodUpdates(t *testing.T) {
+	otherRunAsUser := int64(1000)
+
+	tests := []struct {
+		name        string
+		reviseFunc  func(*corev1.PodTemplateSpec)
+		wantChanged bool
+	}{
+		{
+			name:        "no changes",
+			reviseFunc:  func(revised *corev1.PodTemplateSpec) {},
+			wantChanged: false,
+		},
+		{
+			name: "env var value changes",
+			reviseFunc: func(revised *corev1.PodTemplateSpec) {
+				revised.Spec.Containers[0].Env[0].Value = "splunk_spark_worker"
+			},
+			wantChanged: true,
+		},
+		{
+			name: "env var dropped",
+			reviseFunc: func(revised *corev1.PodTemplateSpec) {
+				revised.Spec.Containers[0].Env = nil
+			},
+			wantChanged: true,
+		},
+		{
+			name: "envFrom added",
+			reviseFunc: func(revised *corev1.PodTemplateSpec) {
+				revised.Spec.Containers[0].EnvFrom = []corev1.EnvFromSource{
+					{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "splunk-config"}}},
+				}
+			},
+			wantChanged: true,
+		},
+		{
+			name: "liveness probe added",
+			reviseFunc: func(revised *corev1.PodTemplateSpec) {
+				revised.Spec.Containers[0].LivenessProbe = &corev1.Probe{
+					Handler: corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz"}},
+				}
+			},
+			wantChanged: true,
+		},
+		{
+			name: "readiness probe added",
+			reviseFunc: func(revised *corev1.PodTemplateSpec) {
+				revised.Spec.Containers[0].ReadinessProbe = &corev1.Probe{
+					Handler: corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz"}},
+				}
+			},
+			wantChanged: true,
+		},
+		{
+			name: "startup probe added",
+			reviseFunc: func(revised *corev1.PodTemplateSpec) {
+				revised.Spec.Containers[0].StartupProbe = &corev1.Probe{
+					Handler: corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz"}},
+				}
+			},
+			wantChanged: true,
+		},
+		{
+			name: "container command changes",
+			reviseFunc: func(revised *corev1.PodTemplateSpec) {
+				revised.Spec.Containers[0].Command = []string{"/bin/sh", "-c", "entrypoint.sh"}
+			},
+			wantChanged: true,
+		},
+		{
+			name: "container args change",
+			reviseFunc: func(revised *corev1.PodTemplateSpec) {
+				revised.Spec.Containers[0].Args = []string{"--verbose"}
+			},
+			wantChanged: true,
+		},
+		{
+			name: "container security context changes",
+			reviseFunc: func(revised *corev1.PodTemplateSpec) {
+				revised.Spec.Containers[0].SecurityContext = &corev1.SecurityContext{RunAsUser: &otherRunAsUser}
+			},
+			wantChanged: true,
+		},
+		{
+			name: "pod security context changes",
+			reviseFunc: func(revised *corev1.PodTemplateSpec) {
+				revised.Spec.SecurityContext = &corev1.PodSecurityContext{RunAsUser: &otherRunAsUser}
+			},
+			wantChanged: true,
+		},
+		{
+			name: "tolerations added",
+			reviseFunc: func(revised *corev1.PodTemplateSpec) {
+				revised.Spec.Tolerations = []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}}
+			},
+			wantChanged: true,
+		},
+		{
+			name: "node selector added",
+			reviseFunc: func(revised *corev1.PodTemplateSpec) {
+				revised.Spec.NodeSelector = map[string]string{"disktype": "ssd"}
+			},
+			wantChanged: true,
+		},
+		{
+			name: "service account name changes",
+			reviseFunc: func(revised *corev1.PodTemplateSpec) {
+				revised.Spec.ServiceAccountName = "splunk-operator"
+			},
+			wantChanged: true,
+		},
+		{
+			name: "image pull secrets added",
+			reviseFunc: func(revised *corev1.PodTemplateSpec) {
+				revised.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "regcred"}}
+			},
+			wantChanged: true,
+		},
+		{
+			name: "volumes added",
+			reviseFunc: func(revised *corev1.PodTemplateSpec) {
+				revised.Spec.Volumes = []corev1.Volume{{Name: "data"}}
+			},
+			wantChanged: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := basePodTemplate()
+			revised := basePodTemplate()
+			tt.reviseFunc(revised)
+
+			got := MergePodUpdates(current, revised, "splunk-test")
+			if got != tt.wantChanged {
+				t.Errorf("MergePodUpdates() = %v, want %v", got, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestMergePodUpdatesEnvOrderInsensitive(t *testing.T) {
+	current := basePodTemplate()
+	current.Spec.Containers[0].Env = append(current.Spec.Containers[0].Env, corev1.EnvVar{Name: "ANOTHER", Value: "1"})
+
+	revised := basePodTemplate()
+	revised.Spec.Containers[0].Env = []corev1.EnvVar{
+		{Name: "ANOTHER", Value: "1"},
+		{Name: "SPLUNK_ROLE", Value: "splunk_spark_master"},
+	}
+
+	if got := MergePodUpdates(current, revised, "splunk-test"); got {
+		t.Errorf("MergePodUpdates() = %v, want false for reordered but otherwise identical env vars", got)
+	}
+}
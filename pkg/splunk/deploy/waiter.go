@@ -0,0 +1,192 @@
+// Copyright (c) 2018-2020 Splunk Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// resourcePollInterval is how often WaitForResources re-checks resource status.
+const resourcePollInterval = 2 * time.Second
+
+// IsReady reports whether obj has reached a ready state. It returns true when
+// the object is ready. When it is not ready, it returns false along with an
+// error describing the reason (this is not a failure to be propagated, just
+// the most recent unready condition). An error is also returned if obj's kind
+// is not one we know how to assess.
+func IsReady(obj ResourceObject) (bool, error) {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return isDeploymentReady(o)
+	case *appsv1.StatefulSet:
+		return isStatefulSetReady(o)
+	case *corev1.Service:
+		return isServiceReady(o)
+	case *corev1.Pod:
+		return isPodReady(o)
+	default:
+		return false, fmt.Errorf("IsReady: unsupported resource kind %T", obj)
+	}
+}
+
+// isDeploymentReady checks generation and replica counts to decide whether a
+// Deployment's latest ReplicaSet has finished rolling out.
+func isDeploymentReady(deployment *appsv1.Deployment) (bool, error) {
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return false, fmt.Errorf("waiting for deployment spec update to be observed")
+	}
+
+	for _, cond := range deployment.Status.Conditions {
+		if cond.Type == appsv1.DeploymentProgressing && (cond.Status != corev1.ConditionTrue || cond.Reason != "NewReplicaSetAvailable") {
+			return false, fmt.Errorf("deployment %q is not progressing: %s", deployment.GetName(), cond.Reason)
+		}
+	}
+
+	if deployment.Spec.Replicas == nil {
+		return false, fmt.Errorf("deployment %q has no replica count set", deployment.GetName())
+	}
+	replicas := *deployment.Spec.Replicas
+
+	if deployment.Status.UpdatedReplicas < replicas {
+		return false, fmt.Errorf("%d of %d replicas updated", deployment.Status.UpdatedReplicas, replicas)
+	}
+	if deployment.Status.AvailableReplicas < replicas {
+		return false, fmt.Errorf("%d of %d replicas available", deployment.Status.AvailableReplicas, replicas)
+	}
+
+	return true, nil
+}
+
+// isStatefulSetReady mirrors the rollout checks used for Deployments, adjusted
+// for the partitioned rolling-update semantics StatefulSets support.
+func isStatefulSetReady(sts *appsv1.StatefulSet) (bool, error) {
+	if sts.Status.ObservedGeneration < sts.Generation {
+		return false, fmt.Errorf("waiting for statefulset spec update to be observed")
+	}
+
+	if sts.Spec.Replicas == nil {
+		return false, fmt.Errorf("statefulset %q has no replica count set", sts.GetName())
+	}
+	replicas := *sts.Spec.Replicas
+
+	if sts.Status.ReadyReplicas < replicas {
+		return false, fmt.Errorf("%d of %d replicas ready", sts.Status.ReadyReplicas, replicas)
+	}
+
+	if sts.Spec.UpdateStrategy.Type == appsv1.RollingUpdateStatefulSetStrategyType {
+		var partition int32
+		if p := sts.Spec.UpdateStrategy.RollingUpdate; p != nil && p.Partition != nil {
+			partition = *p.Partition
+		}
+		if sts.Status.UpdatedReplicas < replicas-partition {
+			return false, fmt.Errorf("%d of %d updated replicas rolled out", sts.Status.UpdatedReplicas, replicas-partition)
+		}
+	}
+
+	return true, nil
+}
+
+// isServiceReady treats ClusterIP and headless Services as ready as soon as
+// they exist, since there is nothing further for the API server to converge.
+// LoadBalancer Services are only ready once the cloud provider has assigned
+// an ingress address.
+func isServiceReady(service *corev1.Service) (bool, error) {
+	if service.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return true, nil
+	}
+
+	if len(service.Status.LoadBalancer.Ingress) == 0 {
+		return false, fmt.Errorf("load balancer %q has no ingress address assigned", service.GetName())
+	}
+
+	return true, nil
+}
+
+// isPodReady checks the standard Pod "Ready" condition.
+func isPodReady(pod *corev1.Pod) (bool, error) {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			if cond.Status == corev1.ConditionTrue {
+				return true, nil
+			}
+			return false, fmt.Errorf("pod %q is not ready: %s", pod.GetName(), cond.Reason)
+		}
+	}
+	return false, fmt.Errorf("pod %q has no Ready condition yet", pod.GetName())
+}
+
+// WaitForResources polls objs until every one of them reports ready via
+// IsReady, the context is cancelled, or timeout elapses. It refreshes each
+// object from the API server on every poll so that status fields reflect the
+// latest observed state. On timeout it returns an error joining the names and
+// most recent failure reasons of every resource that never became ready.
+func WaitForResources(ctx context.Context, c client.Client, timeout time.Duration, objs []ResourceObject) error {
+	scopedLog := log.WithName("WaitForResources")
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	reasons := make(map[string]string, len(objs))
+
+	err := wait.PollImmediateUntil(resourcePollInterval, func() (bool, error) {
+		allReady := true
+
+		for _, obj := range objs {
+			key := types.NamespacedName{
+				Namespace: obj.GetObjectMeta().GetNamespace(),
+				Name:      obj.GetObjectMeta().GetName(),
+			}
+
+			if err := c.Get(ctx, key, obj); err != nil {
+				reasons[key.String()] = err.Error()
+				allReady = false
+				continue
+			}
+
+			ready, reasonErr := IsReady(obj)
+			if !ready {
+				reasons[key.String()] = reasonErr.Error()
+				allReady = false
+				continue
+			}
+
+			delete(reasons, key.String())
+		}
+
+		return allReady, nil
+	}, ctx.Done())
+
+	if err == nil {
+		scopedLog.Info("All resources are ready")
+		return nil
+	}
+
+	var msgs []string
+	for name, reason := range reasons {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", name, reason))
+	}
+	scopedLog.Info("Timed out waiting for resources to become ready", "unready", msgs)
+
+	return fmt.Errorf("timed out waiting for resources to become ready: %s", strings.Join(msgs, "; "))
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2018-2020 Splunk Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podio
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/splunk/splunk-operator/pkg/splunk/spark"
+)
+
+// Handler proxies pod logs and exec requests over HTTP, so operators can
+// reach them through the operator's own metrics port instead of kubectl.
+// Pods are resolved by the same label selectors GetSparkAppLabels already
+// uses to select them for Services, so the endpoints stay stable across pod
+// restarts instead of requiring callers to track individual pod names.
+//
+// Every request must carry a bearer token that authenticates against the API
+// server (see authenticate); /logs and /exec both run with the operator's
+// own, typically cluster-wide, ServiceAccount permissions, so this handler
+// must never be reachable by an unauthenticated caller.
+type Handler struct {
+	Clientset  kubernetes.Interface
+	RestConfig *rest.Config
+}
+
+// ServeHTTP dispatches requests of the form:
+//
+//	GET  /logs?namespace=ns&identifier=foo&component=spark-master&container=spark&tail=100&follow=true
+//	POST /exec?namespace=ns&identifier=foo&component=spark-master&container=spark&cmd=/bin/sh&cmd=-c&cmd=ps+aux
+//
+// Both require an "Authorization: Bearer <token>" header that authenticate
+// accepts, then select the first Running pod matching the
+// identifier/component selector. /logs streams that pod's logs to the
+// response body. /exec runs cmd (repeated "cmd" query params, in order)
+// inside it, wiring the request body to the command's stdin and the
+// response body to its combined stdout/stderr.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h.authenticate(r); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/logs":
+		h.serveLogs(w, r)
+	case "/exec":
+		h.serveExec(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// authenticate requires an "Authorization: Bearer <token>" header and
+// validates the token against the API server via TokenReview. This handler
+// is mounted on the operator's metrics listener, which is otherwise
+// unauthenticated, so without this check any network caller able to reach
+// that port could run arbitrary commands inside any pod /exec's selector
+// matches, using the operator's own ServiceAccount.
+func (h *Handler) authenticate(r *http.Request) error {
+	const prefix = "Bearer "
+
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, prefix) {
+		return fmt.Errorf("missing bearer token")
+	}
+	token := strings.TrimPrefix(header, prefix)
+	if token == "" {
+		return fmt.Errorf("missing bearer token")
+	}
+
+	review, err := h.Clientset.AuthenticationV1().TokenReviews().Create(r.Context(), &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("token review failed: %s", err)
+	}
+	if !review.Status.Authenticated {
+		return fmt.Errorf("token not authenticated: %s", review.Status.Error)
+	}
+
+	return nil
+}
+
+func (h *Handler) serveLogs(w http.ResponseWriter, r *http.Request) {
+	scopedLog := log.WithName("serveLogs")
+
+	ns := r.URL.Query().Get("namespace")
+	identifier := r.URL.Query().Get("identifier")
+	component := r.URL.Query().Get("component")
+	container := r.URL.Query().Get("container")
+
+	if ns == "" || identifier == "" || component == "" {
+		http.Error(w, "namespace, identifier and component are required", http.StatusBadRequest)
+		return
+	}
+
+	pod, err := h.findPod(r.Context(), ns, identifier, component)
+	if err != nil {
+		scopedLog.Error(err, "Failed to find matching pod", "namespace", ns, "identifier", identifier, "component", component)
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	opts := LogOptions{
+		Follow: r.URL.Query().Get("follow") == "true",
+	}
+	if tail := r.URL.Query().Get("tail"); tail != "" {
+		if n, err := strconv.ParseInt(tail, 10, 64); err == nil {
+			opts.TailLines = n
+		}
+	}
+
+	stream, err := StreamLogs(r.Context(), h.RestConfig, ns, pod.Name, container, opts)
+	if err != nil {
+		scopedLog.Error(err, "Failed to stream logs", "pod", pod.Name)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer stream.Close()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	buf := make([]byte, 4096)
+	for {
+		n, err := stream.Read(buf)
+		if n > 0 {
+			if _, writeErr := w.Write(buf[:n]); writeErr != nil {
+				return
+			}
+			if flusher, ok := w.(http.Flusher); ok {
+				flusher.Flush()
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (h *Handler) serveExec(w http.ResponseWriter, r *http.Request) {
+	scopedLog := log.WithName("serveExec")
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ns := r.URL.Query().Get("namespace")
+	identifier := r.URL.Query().Get("identifier")
+	component := r.URL.Query().Get("component")
+	container := r.URL.Query().Get("container")
+	cmd := r.URL.Query()["cmd"]
+
+	if ns == "" || identifier == "" || component == "" || len(cmd) == 0 {
+		http.Error(w, "namespace, identifier, component and cmd are required", http.StatusBadRequest)
+		return
+	}
+
+	pod, err := h.findPod(r.Context(), ns, identifier, component)
+	if err != nil {
+		scopedLog.Error(err, "Failed to find matching pod", "namespace", ns, "identifier", identifier, "component", component)
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	err = Exec(r.Context(), h.RestConfig, ns, pod.Name, container, cmd, r.Body, w, w)
+	if err != nil {
+		scopedLog.Error(err, "Exec failed", "pod", pod.Name, "cmd", cmd)
+	}
+}
+
+// findPod returns the first Running pod matching the selector
+// GetSparkAppLabels(identifier, component, true) produces.
+func (h *Handler) findPod(ctx context.Context, ns string, identifier string, component string) (*corev1.Pod, error) {
+	selector := labels.SelectorFromSet(spark.GetSparkAppLabels(identifier, component, true))
+
+	pods, err := h.Clientset.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning {
+			return &pod, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no running pod found matching selector %q in namespace %q", selector.String(), ns)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2018-2020 Splunk Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podio
+
+import (
+	"strings"
+	"sync"
+)
+
+// LogBuffer is a bounded, line-oriented ring buffer of recent log output. A
+// reconciler copies a StreamLogs response into one each reconcile (e.g. via
+// io.Copy) and reads it back with String to populate a status field such as
+// SparkMasterLogs/SparkWorkerLogs, without retaining unbounded log history.
+// It is safe for concurrent use.
+type LogBuffer struct {
+	// MaxLines is the number of most recent lines retained. Zero or a
+	// negative value retains every line.
+	MaxLines int
+
+	mu      sync.Mutex
+	lines   []string
+	partial string
+}
+
+// NewLogBuffer returns a LogBuffer retaining at most maxLines lines (every
+// line when maxLines is zero or negative).
+func NewLogBuffer(maxLines int) *LogBuffer {
+	return &LogBuffer{MaxLines: maxLines}
+}
+
+// Write implements io.Writer, splitting p on newlines and appending each
+// completed line, discarding the oldest lines once MaxLines is exceeded.
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.partial += string(p)
+	for {
+		idx := strings.IndexByte(b.partial, '\n')
+		if idx < 0 {
+			break
+		}
+		b.appendLocked(b.partial[:idx])
+		b.partial = b.partial[idx+1:]
+	}
+
+	return len(p), nil
+}
+
+// appendLocked adds line to the buffer, trimming to MaxLines. Callers must
+// hold b.mu.
+func (b *LogBuffer) appendLocked(line string) {
+	b.lines = append(b.lines, line)
+	if b.MaxLines > 0 && len(b.lines) > b.MaxLines {
+		b.lines = b.lines[len(b.lines)-b.MaxLines:]
+	}
+}
+
+// String returns the buffered lines joined with newlines, oldest first,
+// including any line not yet terminated by a trailing newline.
+func (b *LogBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	lines := b.lines
+	if b.partial != "" {
+		lines = append(append([]string{}, lines...), b.partial)
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2018-2020 Splunk Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package podio provides in-process log streaming and exec access to Spark
+// and Splunk pods, so operators debugging a failing pod do not have to reach
+// for kubectl by hand.
+package podio
+
+import (
+	"context"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// logger used by splunk.podio package
+var log = logf.Log.WithName("splunk.podio")
+
+// LogOptions controls how StreamLogs retrieves a container's log output.
+type LogOptions struct {
+	// Follow keeps the stream open and returns new log lines as they are written.
+	Follow bool
+
+	// SinceSeconds restricts output to lines written within the last N seconds. Zero means unbounded.
+	SinceSeconds int64
+
+	// TailLines restricts output to the last N lines. Zero means unbounded.
+	TailLines int64
+}
+
+// StreamLogs returns a ReadCloser of the logs for container in pod ns/pod,
+// honoring opts. Callers are responsible for closing the returned stream,
+// which also unblocks the underlying HTTP connection when Follow is set.
+func StreamLogs(ctx context.Context, restCfg *rest.Config, ns string, pod string, container string, opts LogOptions) (io.ReadCloser, error) {
+	scopedLog := log.WithName("StreamLogs").WithValues("namespace", ns, "pod", pod, "container", container)
+
+	clientset, err := kubernetes.NewForConfig(restCfg)
+	if err != nil {
+		scopedLog.Error(err, "Failed to create Kubernetes clientset")
+		return nil, err
+	}
+
+	podLogOpts := &corev1.PodLogOptions{
+		Container: container,
+		Follow:    opts.Follow,
+	}
+	if opts.SinceSeconds > 0 {
+		podLogOpts.SinceSeconds = &opts.SinceSeconds
+	}
+	if opts.TailLines > 0 {
+		podLogOpts.TailLines = &opts.TailLines
+	}
+
+	req := clientset.CoreV1().Pods(ns).GetLogs(pod, podLogOpts)
+
+	stream, err := req.Stream(ctx)
+	if err != nil {
+		scopedLog.Error(err, "Failed to open log stream")
+		return nil, err
+	}
+
+	return stream, nil
+}
+
+// Exec runs cmd inside container in pod ns/pod, wiring stdin/stdout/stderr to
+// the caller's streams over an SPDY connection. It returns once the remote
+// command exits or ctx is cancelled.
+func Exec(ctx context.Context, restCfg *rest.Config, ns string, pod string, container string, cmd []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	scopedLog := log.WithName("Exec").WithValues("namespace", ns, "pod", pod, "container", container)
+
+	clientset, err := kubernetes.NewForConfig(restCfg)
+	if err != nil {
+		scopedLog.Error(err, "Failed to create Kubernetes clientset")
+		return err
+	}
+
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(pod).
+		Namespace(ns).
+		SubResource("exec")
+
+	req.VersionedParams(&corev1.PodExecOptions{
+		Container: container,
+		Command:   cmd,
+		Stdin:     stdin != nil,
+		Stdout:    stdout != nil,
+		Stderr:    stderr != nil,
+	}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(restCfg, "POST", req.URL())
+	if err != nil {
+		scopedLog.Error(err, "Failed to create SPDY executor")
+		return err
+	}
+
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+	if err != nil {
+		scopedLog.Error(err, "Exec stream ended with an error")
+	}
+
+	return err
+}
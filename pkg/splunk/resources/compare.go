@@ -0,0 +1,76 @@
+// Copyright (c) 2018-2020 Splunk Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// CompareEnvs returns true if current and revised differ, ignoring ordering.
+// Kubernetes does not guarantee a stable order for env vars assembled from
+// multiple sources, so comparing positionally would report spurious drift.
+func CompareEnvs(current []corev1.EnvVar, revised []corev1.EnvVar) bool {
+	if len(current) != len(revised) {
+		return true
+	}
+
+	sortEnvVars := func(envs []corev1.EnvVar) []corev1.EnvVar {
+		sorted := make([]corev1.EnvVar, len(envs))
+		copy(sorted, envs)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+		return sorted
+	}
+
+	return !reflect.DeepEqual(sortEnvVars(current), sortEnvVars(revised))
+}
+
+// CompareEnvFrom returns true if current and revised differ.
+func CompareEnvFrom(current []corev1.EnvFromSource, revised []corev1.EnvFromSource) bool {
+	return CompareByMarshall(current, revised)
+}
+
+// CompareTolerations returns true if current and revised differ.
+func CompareTolerations(current []corev1.Toleration, revised []corev1.Toleration) bool {
+	return CompareByMarshall(current, revised)
+}
+
+// CompareNodeSelector returns true if current and revised differ.
+func CompareNodeSelector(current map[string]string, revised map[string]string) bool {
+	return !reflect.DeepEqual(current, revised)
+}
+
+// CompareImagePullSecrets returns true if current and revised differ.
+func CompareImagePullSecrets(current []corev1.LocalObjectReference, revised []corev1.LocalObjectReference) bool {
+	return CompareByMarshall(current, revised)
+}
+
+// CompareVolumes returns true if current and revised differ, ignoring ordering.
+func CompareVolumes(current []corev1.Volume, revised []corev1.Volume) bool {
+	if len(current) != len(revised) {
+		return true
+	}
+
+	sortVolumes := func(vols []corev1.Volume) []corev1.Volume {
+		sorted := make([]corev1.Volume, len(vols))
+		copy(sorted, vols)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+		return sorted
+	}
+
+	return !reflect.DeepEqual(sortVolumes(current), sortVolumes(revised))
+}
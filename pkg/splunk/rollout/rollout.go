@@ -0,0 +1,258 @@
+// Copyright (c) 2018-2020 Splunk Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rollout resolves auto-update annotations on a SplunkEnterprise
+// resource into controlled image rollouts for its Spark and Splunk pods.
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/splunk/splunk-operator/pkg/splunk/deploy"
+)
+
+// logger used by splunk.rollout package
+var log = logf.Log.WithName("splunk.rollout")
+
+// Mode selects how a component's image is kept up to date.
+type Mode string
+
+const (
+	// ModeDisabled leaves the image exactly as configured on the SplunkEnterprise spec.
+	ModeDisabled Mode = "disabled"
+
+	// ModeLocal only rolls a component when the user bumps its image field on the spec.
+	ModeLocal Mode = "local"
+
+	// ModeRegistry periodically resolves the configured tag to its remote
+	// digest and rolls the component whenever that digest changes.
+	ModeRegistry Mode = "registry"
+)
+
+const (
+	// AutoUpdateAnnotation is the default auto-update mode for all components,
+	// overridden per-component by AutoUpdateAnnotation + "-" + component (e.g.
+	// "splunk.com/auto-update-spark-master").
+	AutoUpdateAnnotation = "splunk.com/auto-update"
+
+	// ImageDigestAnnotation records the digest last pinned onto a child
+	// Deployment/StatefulSet's pod template, so the next check has something to diff against.
+	ImageDigestAnnotation = "splunk.com/image-digest"
+)
+
+const (
+	// EventImageRolloutStarted is emitted when a new digest is pinned onto a pod template.
+	EventImageRolloutStarted = "ImageRolloutStarted"
+
+	// EventImageRolloutSkipped is emitted when a check runs but finds nothing to roll.
+	EventImageRolloutSkipped = "ImageRolloutSkipped"
+)
+
+// GetMode returns the auto-update mode configured for component, falling back
+// to the CR-wide annotation and finally ModeDisabled if neither is set.
+func GetMode(annotations map[string]string, component string) Mode {
+	if v, ok := annotations[fmt.Sprintf("%s-%s", AutoUpdateAnnotation, component)]; ok {
+		return Mode(v)
+	}
+	if v, ok := annotations[AutoUpdateAnnotation]; ok {
+		return Mode(v)
+	}
+	return ModeDisabled
+}
+
+// ResolveDigest resolves image's current remote digest (e.g.
+// "splunk/spark:latest" -> "index.docker.io/splunk/spark@sha256:...") using the registry's
+// published manifest, without pulling the image itself.
+func ResolveDigest(ctx context.Context, image string) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", fmt.Errorf("ResolveDigest: %s", err)
+	}
+
+	desc, err := remote.Get(ref, remote.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("ResolveDigest: %s", err)
+	}
+
+	return fmt.Sprintf("%s@%s", ref.Context().Name(), desc.Digest.String()), nil
+}
+
+// CheckResult reports the outcome of a single CheckAndPinImage call.
+type CheckResult struct {
+	// Rolled is true if PodTemplate's image was pinned to a new digest.
+	Rolled bool
+
+	// Digest is the digest observed on this check (whether or not it changed).
+	Digest string
+
+	// CheckedAt is when the registry was queried.
+	CheckedAt time.Time
+}
+
+// CheckAndPinImage resolves image's current digest and, if it differs from
+// the digest recorded in podTemplate's ImageDigestAnnotation, pins
+// "image@sha256:..." onto every container in podTemplate matching
+// containerName and updates the annotation. This mutates podTemplate directly
+// so the caller's subsequent Update/Apply call (and the MergePodUpdates diff
+// it triggers) picks up the change. recorder, if non-nil, emits
+// ImageRolloutStarted/ImageRolloutSkipped events against obj, which should be
+// the child Deployment or StatefulSet being rolled.
+func CheckAndPinImage(ctx context.Context, recorder record.EventRecorder, obj runtime.Object, podTemplate *corev1.PodTemplateSpec, containerName string, image string) (CheckResult, error) {
+	scopedLog := log.WithName("CheckAndPinImage").WithValues("container", containerName, "image", image)
+
+	digest, err := ResolveDigest(ctx, image)
+	if err != nil {
+		scopedLog.Error(err, "Failed to resolve remote digest")
+		return CheckResult{}, err
+	}
+
+	result := CheckResult{Digest: digest, CheckedAt: time.Now()}
+
+	if podTemplate.Annotations[ImageDigestAnnotation] == digest {
+		scopedLog.Info("Image digest unchanged", "digest", digest)
+		if recorder != nil {
+			recorder.Eventf(obj, corev1.EventTypeNormal, EventImageRolloutSkipped, "Image %s is already pinned to %s", image, digest)
+		}
+		return result, nil
+	}
+
+	for idx := range podTemplate.Spec.Containers {
+		if podTemplate.Spec.Containers[idx].Name != containerName {
+			continue
+		}
+		podTemplate.Spec.Containers[idx].Image = digest
+	}
+
+	if podTemplate.Annotations == nil {
+		podTemplate.Annotations = map[string]string{}
+	}
+	podTemplate.Annotations[ImageDigestAnnotation] = digest
+
+	scopedLog.Info("Pinned container image to resolved digest", "digest", digest)
+	if recorder != nil {
+		recorder.Eventf(obj, corev1.EventTypeNormal, EventImageRolloutStarted, "Rolling %s to %s", containerName, digest)
+	}
+
+	result.Rolled = true
+	return result, nil
+}
+
+// Target is one component CheckAndPinImage should be run against on each
+// Runner tick.
+type Target struct {
+	// Obj is the child Deployment or StatefulSet owning PodTemplate. Runner
+	// updates it via the API server when CheckAndPinImage pins a new digest.
+	Obj deploy.ResourceObject
+
+	// Component identifies this target to GetMode (e.g. "spark-master").
+	Component string
+
+	// Annotations is the owning SplunkEnterprise resource's annotations,
+	// consulted by GetMode to decide Component's auto-update mode.
+	Annotations map[string]string
+
+	// PodTemplate is mutated in place by CheckAndPinImage.
+	PodTemplate *corev1.PodTemplateSpec
+
+	// Container is the name of the container within PodTemplate to pin.
+	Container string
+
+	// Image is the currently configured image reference (e.g. "splunk/spark:latest").
+	Image string
+}
+
+// GetTargetsFunc lists the components a Runner should check on each tick.
+type GetTargetsFunc func(ctx context.Context) ([]Target, error)
+
+// Runner is a manager.Runnable that periodically resolves registry digests
+// for every Target with Mode ModeRegistry and pins any that changed.
+//
+// A reconciler wiring Runner into a SplunkEnterprise's status should persist
+// each checked Target's CheckResult.CheckedAt and CheckResult.Digest onto
+// that component's Status.LastChecked and resolved-image fields; Runner
+// itself only mutates the live Deployment/StatefulSet and does not touch CR
+// status, since it has no SplunkEnterprise to write back to (GetTargets may
+// aggregate targets from several SplunkEnterprise resources).
+type Runner struct {
+	// Client updates the child Deployment/StatefulSet after a successful pin.
+	Client client.Client
+
+	// Recorder, if non-nil, is passed through to CheckAndPinImage.
+	Recorder record.EventRecorder
+
+	// Interval between checks of all targets.
+	Interval time.Duration
+
+	// GetTargets lists the components to check on each tick.
+	GetTargets GetTargetsFunc
+}
+
+// Start implements manager.Runnable. It blocks, checking all targets once
+// per Interval, until ctx is canceled.
+func (r *Runner) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			r.checkAll(ctx)
+		}
+	}
+}
+
+// checkAll runs CheckAndPinImage against every target in ModeRegistry,
+// updating the corresponding object when the check pins a new digest.
+func (r *Runner) checkAll(ctx context.Context) {
+	scopedLog := log.WithName("checkAll")
+
+	targets, err := r.GetTargets(ctx)
+	if err != nil {
+		scopedLog.Error(err, "Failed to list rollout targets")
+		return
+	}
+
+	for _, target := range targets {
+		if GetMode(target.Annotations, target.Component) != ModeRegistry {
+			continue
+		}
+
+		result, err := CheckAndPinImage(ctx, r.Recorder, target.Obj, target.PodTemplate, target.Container, target.Image)
+		if err != nil {
+			scopedLog.Error(err, "Failed to check image", "component", target.Component)
+			continue
+		}
+
+		if !result.Rolled {
+			continue
+		}
+
+		if err := deploy.UpdateResource(r.Client, target.Obj); err != nil {
+			scopedLog.Error(err, "Failed to update resource with pinned image", "component", target.Component)
+		}
+	}
+}
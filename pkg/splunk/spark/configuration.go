@@ -216,8 +216,96 @@ func GetSparkDeployment(cr *v1alpha2.SplunkEnterprise, instanceType InstanceType
 	return deployment, nil
 }
 
-// GetSparkService returns a Kubernetes Service object for Spark instances configured for a SplunkEnterprise resource.
-func GetSparkService(cr *v1alpha2.SplunkEnterprise, instanceType InstanceType, isHeadless bool, ports []corev1.ServicePort) *corev1.Service {
+// sparkServiceExposureOrder fixes the order GetSparkService considers
+// exposure types in. Ranging over the `grouped` map directly would make the
+// returned slice (and therefore GenerateSparkManifest/WriteYAML's output) vary
+// across calls, since Go map iteration order is unspecified.
+var sparkServiceExposureOrder = []v1alpha2.SparkServiceExposureType{
+	v1alpha2.SparkServiceExposureClusterIP,
+	v1alpha2.SparkServiceExposureNodePort,
+	v1alpha2.SparkServiceExposureLoadBalancer,
+	v1alpha2.SparkServiceExposureIngress,
+}
+
+// GetSparkService returns the Services needed to expose ports for instanceType
+// as configured by exposures (keyed by ServicePort name). Ports left unset in
+// exposures keep the historical behavior of a single ClusterIP (or headless)
+// Service. Ports marked Disabled are omitted entirely. Each distinct
+// Kubernetes Service type requested produces its own Service object, since a
+// single Service cannot mix types; ports selecting Ingress get a ClusterIP
+// Service of their own so GetSparkIngress has a stable backend to route to.
+// Pass a nil exposures map to get the original single-Service behavior.
+func GetSparkService(cr *v1alpha2.SplunkEnterprise, instanceType InstanceType, isHeadless bool, ports []corev1.ServicePort, exposures map[string]v1alpha2.SparkServiceExposure) []*corev1.Service {
+	grouped := map[v1alpha2.SparkServiceExposureType][]corev1.ServicePort{}
+
+	for _, port := range ports {
+		exposure := resolveSparkServiceExposure(exposures, port.Name)
+		if exposure.Type == v1alpha2.SparkServiceExposureDisabled {
+			continue
+		}
+		grouped[exposure.Type] = append(grouped[exposure.Type], port)
+	}
+
+	var services []*corev1.Service
+	for _, exposureType := range sparkServiceExposureOrder {
+		groupedPorts, ok := grouped[exposureType]
+		if !ok {
+			continue
+		}
+
+		service := buildSparkService(cr, instanceType, isHeadless && exposureType == v1alpha2.SparkServiceExposureClusterIP, groupedPorts)
+
+		// Every non-default exposure type gets its own name suffix, since
+		// each distinct Kubernetes Service type requested for the same
+		// instanceType would otherwise collide on the default name and
+		// silently clobber each other on apply (ApplyService treats an
+		// existing name as nothing to do) or produce duplicate
+		// metadata.name documents in an exported manifest.
+		switch exposureType {
+		case v1alpha2.SparkServiceExposureNodePort:
+			service.Name = fmt.Sprintf("%s-nodeport", service.Name)
+			service.Spec.Type = corev1.ServiceTypeNodePort
+			for idx, port := range groupedPorts {
+				if exposure := resolveSparkServiceExposure(exposures, port.Name); exposure.NodePort != 0 {
+					service.Spec.Ports[idx].NodePort = exposure.NodePort
+				}
+			}
+		case v1alpha2.SparkServiceExposureLoadBalancer:
+			service.Name = fmt.Sprintf("%s-loadbalancer", service.Name)
+			service.Spec.Type = corev1.ServiceTypeLoadBalancer
+			for _, port := range groupedPorts {
+				exposure := resolveSparkServiceExposure(exposures, port.Name)
+				if exposure.LoadBalancerIP != "" {
+					service.Spec.LoadBalancerIP = exposure.LoadBalancerIP
+				}
+				if len(exposure.Annotations) > 0 {
+					service.Annotations = resources.MergeMap(service.Annotations, exposure.Annotations)
+				}
+			}
+		case v1alpha2.SparkServiceExposureIngress:
+			service.Name = fmt.Sprintf("%s-ingress", service.Name)
+		}
+
+		services = append(services, service)
+	}
+
+	return services
+}
+
+// resolveSparkServiceExposure returns the exposure configured for portName in
+// exposures, defaulting to a plain ClusterIP Service when the CR does not
+// mention the port (this preserves the behavior of anyone not using the
+// feature).
+func resolveSparkServiceExposure(exposures map[string]v1alpha2.SparkServiceExposure, portName string) v1alpha2.SparkServiceExposure {
+	if exposure, ok := exposures[portName]; ok && exposure.Type != "" {
+		return exposure
+	}
+	return v1alpha2.SparkServiceExposure{Type: v1alpha2.SparkServiceExposureClusterIP}
+}
+
+// buildSparkService returns a single Kubernetes Service object for Spark
+// instances configured for a SplunkEnterprise resource.
+func buildSparkService(cr *v1alpha2.SplunkEnterprise, instanceType InstanceType, isHeadless bool, ports []corev1.ServicePort) *corev1.Service {
 
 	serviceName := GetSparkServiceName(instanceType, cr.GetIdentifier(), isHeadless)
 	serviceTypeLabels := GetSparkAppLabels(cr.GetIdentifier(), fmt.Sprintf("%s-%s", instanceType, "service"), false)
@@ -0,0 +1,97 @@
+// Copyright (c) 2018-2020 Splunk Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// 	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spark
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/splunk/splunk-operator/pkg/apis/enterprise/v1alpha2"
+	"github.com/splunk/splunk-operator/pkg/splunk/resources"
+)
+
+// GetSparkIngress returns a networking/v1 Ingress routing host/path traffic to
+// the ClusterIP Services GetSparkService creates for any port exposed as
+// Ingress. The Ingress has a single rule holding one "/" path per exposed
+// port. Its host, IngressClassName and TLS secret are the last non-empty
+// values found among those ports' exposures; per-port hosts are not kept.
+func GetSparkIngress(cr *v1alpha2.SplunkEnterprise, instanceType InstanceType, ports []corev1.ServicePort, exposures map[string]v1alpha2.SparkServiceExposure) *networkingv1.Ingress {
+	pathType := networkingv1.PathTypePrefix
+	var rule networkingv1.IngressRule
+	var ingressClassName *string
+	var tlsSecretName, host string
+
+	for _, port := range ports {
+		exposure := resolveSparkServiceExposure(exposures, port.Name)
+		if exposure.Type != v1alpha2.SparkServiceExposureIngress {
+			continue
+		}
+
+		if exposure.IngressClassName != "" {
+			ingressClassName = &exposure.IngressClassName
+		}
+		if exposure.Host != "" {
+			host = exposure.Host
+		}
+		if exposure.TLSSecretName != "" {
+			tlsSecretName = exposure.TLSSecretName
+		}
+
+		rule.Host = host
+		if rule.HTTP == nil {
+			rule.HTTP = &networkingv1.HTTPIngressRuleValue{}
+		}
+		rule.HTTP.Paths = append(rule.HTTP.Paths, networkingv1.HTTPIngressPath{
+			Path:     "/",
+			PathType: &pathType,
+			Backend: networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{
+					Name: fmt.Sprintf("%s-ingress", GetSparkServiceName(instanceType, cr.GetIdentifier(), false)),
+					Port: networkingv1.ServiceBackendPort{Name: port.Name},
+				},
+			},
+		})
+	}
+
+	ingress := &networkingv1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Ingress",
+			APIVersion: "networking.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-ingress", GetSparkServiceName(instanceType, cr.GetIdentifier(), false)),
+			Namespace: cr.Namespace,
+			Labels:    GetSparkAppLabels(cr.GetIdentifier(), fmt.Sprintf("%s-%s", instanceType, "ingress"), false),
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ingressClassName,
+			Rules:            []networkingv1.IngressRule{rule},
+		},
+	}
+
+	if tlsSecretName != "" {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{
+			Hosts:      []string{host},
+			SecretName: tlsSecretName,
+		}}
+	}
+
+	ingress.SetOwnerReferences(append(ingress.GetOwnerReferences(), resources.AsOwner(cr)))
+
+	return ingress
+}